@@ -0,0 +1,23 @@
+package pool
+
+// Logger receives pool lifecycle events. Implementations can route these to
+// zap, zerolog, slog, or wherever else a consumer's logs already go.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// noopLogger discards everything. It's the default, so importing this
+// package never injects log output into a binary that hasn't opted in.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...any) {}
+func (noopLogger) Infof(string, ...any)  {}
+func (noopLogger) Warnf(string, ...any)  {}
+
+// loggerBox exists so Pool's atomic.Value always stores the same concrete
+// type, regardless of which Logger implementation callers plug in.
+type loggerBox struct {
+	logger Logger
+}
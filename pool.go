@@ -1,95 +1,404 @@
-// Package pool manages a user-defined set of resources that can be shared among goroutines.
+// Package pool manages a generic, user-defined set of resources that can be
+// shared among goroutines.
 package pool
 
 import (
+	"context"
 	"errors"
-	"io"
-	"log"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ErrPoolClosed is returned when an Acquire returns on a closed pool.
 var ErrPoolClosed = errors.New("Pool has been closed.")
 
-type Pool struct {
-	m         sync.Mutex
-	resources chan io.Closer
-	factory   func() (io.Closer, error)
-	closed    bool
+// defaultEvictInterval is how often the background goroutine checks for
+// idle resources to evict and for the pool to top back up.
+const defaultEvictInterval = time.Minute
+
+// Resource wraps a pooled value of type T with the bookkeeping needed to
+// release or destroy it.
+type Resource[T any] struct {
+	value     T
+	pool      *Pool[T]
+	createdAt time.Time
+}
+
+// Value returns the pooled value. Hold onto it only until Release or Destroy
+// is called.
+func (r *Resource[T]) Value() T {
+	return r.value
 }
 
-// New creates a pool that manages resources.
-// - Requires a function that can allocate a new resource, and the size of the pool.
-func New(fn func() (io.Closer, error), size uint) (*Pool, error) {
-	if size <= 0 {
+// Release returns the resource to its pool.
+func (r *Resource[T]) Release() {
+	r.pool.release(r)
+}
+
+// Destroy removes the resource from circulation, running the pool's
+// destructor on it rather than returning it for reuse.
+func (r *Resource[T]) Destroy() {
+	r.pool.discard(r)
+}
+
+type Pool[T any] struct {
+	// Stats counters. Kept first so they stay 64-bit aligned for atomic
+	// access on 32-bit platforms.
+	acquireCount         uint64
+	acquireDurationNs    int64
+	emptyAcquireCount    uint64
+	canceledAcquireCount uint64
+	constructing         uint64
+
+	m           sync.Mutex
+	resources   chan *Resource[T]
+	constructor func(ctx context.Context) (T, error)
+	destructor  func(T)
+	validate    func(T) bool
+	closed      bool
+	done        chan struct{}
+	minOpen     uint
+	maxOpen     uint
+	numOpen     uint
+	maxLifetime time.Duration
+	logger      atomic.Value // loggerBox
+}
+
+// SetLogger replaces the pool's Logger. A nil logger restores the default
+// no-op logger. Safe to call concurrently with Acquire/Release.
+func (p *Pool[T]) SetLogger(l Logger) {
+	if l == nil {
+		l = noopLogger{}
+	}
+	p.logger.Store(loggerBox{logger: l})
+}
+
+func (p *Pool[T]) log() Logger {
+	if v, ok := p.logger.Load().(loggerBox); ok {
+		return v.logger
+	}
+	return noopLogger{}
+}
+
+// Stats describes a Pool's current pressure and lifetime counters.
+type Stats struct {
+	AcquireCount          uint64
+	AcquireDuration       time.Duration
+	EmptyAcquireCount     uint64
+	CanceledAcquireCount  uint64
+	ConstructingResources uint64
+	IdleResources         uint64
+	TotalResources        uint64
+	MaxResources          uint64
+}
+
+// New creates a pool that manages resources of type T.
+// - constructor allocates a new resource; destructor releases one for good.
+// - minOpen resources are created up front; the pool grows on demand up to
+//   maxOpen, blocking Acquire once that many are in use.
+// - maxLifetime, when > 0, discards and replaces any resource older than it.
+// - validate, when non-nil, is called on a resource before it is handed out;
+//   resources that fail it are discarded and replaced.
+// - A background goroutine periodically evicts idle resources down to
+//   minOpen and tops the pool back up if it ever falls below minOpen.
+func New[T any](constructor func(ctx context.Context) (T, error), destructor func(T), minOpen, maxOpen uint, maxLifetime time.Duration, validate func(T) bool) (*Pool[T], error) {
+	if maxOpen <= 0 {
 		return nil, errors.New("Invalid size value: too small")
 	}
+	if minOpen > maxOpen {
+		return nil, errors.New("Invalid size value: minOpen greater than maxOpen")
+	}
+
+	p := &Pool[T]{
+		constructor: constructor,
+		destructor:  destructor,
+		validate:    validate,
+		resources:   make(chan *Resource[T], maxOpen),
+		done:        make(chan struct{}),
+		minOpen:     minOpen,
+		maxOpen:     maxOpen,
+		maxLifetime: maxLifetime,
+	}
 
-	return &Pool{
-		factory:   fn,
-		resources: make(chan io.Closer, size),
-	}, nil
+	p.SetLogger(nil)
+
+	for i := uint(0); i < minOpen; i++ {
+		r, err := p.newResource(context.Background())
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.resources <- r
+	}
+
+	go p.evictLoop(defaultEvictInterval)
+
+	return p, nil
 }
 
-// Acquire retrieves a resource from the pool.
-func (p *Pool) Acquire() (io.Closer, error) {
-	// Check for a free resource.
-	select {
-	case r, ok := <-p.resources:
-		if !ok {
+// newResource reserves a slot against numOpen and allocates a resource via
+// the constructor, stamping its creation time. Used where the caller has
+// already decided a new resource is warranted (pre-warming, eviction
+// top-up) without needing to check against maxOpen itself.
+func (p *Pool[T]) newResource(ctx context.Context) (*Resource[T], error) {
+	p.m.Lock()
+	p.numOpen++
+	p.m.Unlock()
+
+	return p.construct(ctx)
+}
+
+// construct calls the constructor for a slot against numOpen that the
+// caller has already reserved, rolling the reservation back on failure.
+func (p *Pool[T]) construct(ctx context.Context) (*Resource[T], error) {
+	atomic.AddUint64(&p.constructing, 1)
+	v, err := p.constructor(ctx)
+	atomic.AddUint64(&p.constructing, ^uint64(0))
+
+	if err != nil {
+		p.m.Lock()
+		p.numOpen--
+		p.m.Unlock()
+		return nil, err
+	}
+
+	return &Resource[T]{value: v, pool: p, createdAt: time.Now()}, nil
+}
+
+// expired reports whether r is too old or fails validation and should be
+// discarded rather than handed out.
+func (p *Pool[T]) expired(r *Resource[T]) bool {
+	if p.maxLifetime > 0 && time.Since(r.createdAt) > p.maxLifetime {
+		return true
+	}
+	if p.validate != nil && !p.validate(r.value) {
+		return true
+	}
+	return false
+}
+
+// discard runs the destructor on r's value and removes its bookkeeping.
+func (p *Pool[T]) discard(r *Resource[T]) {
+	p.m.Lock()
+	p.numOpen--
+	p.m.Unlock()
+
+	p.destructor(r.value)
+}
+
+// Acquire retrieves a resource from the pool, discarding and replacing any
+// resource that has expired or failed validation along the way. If the pool
+// is empty and has already opened maxOpen resources, Acquire blocks until
+// one is released or ctx is done, returning ctx.Err() in the latter case. A
+// nil ctx is treated the same as context.Background(), i.e. an unbounded
+// wait.
+func (p *Pool[T]) Acquire(ctx context.Context) (*Resource[T], error) {
+	start := time.Now()
+	r, err := p.acquire(ctx)
+	atomic.AddInt64(&p.acquireDurationNs, int64(time.Since(start)))
+
+	switch err {
+	case nil:
+		atomic.AddUint64(&p.acquireCount, 1)
+	case context.Canceled, context.DeadlineExceeded:
+		atomic.AddUint64(&p.canceledAcquireCount, 1)
+	}
+
+	return r, err
+}
+
+func (p *Pool[T]) acquire(ctx context.Context) (*Resource[T], error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for {
+		select {
+		case r, ok := <-p.resources:
+			if !ok {
+				return nil, ErrPoolClosed
+			}
+			if p.expired(r) {
+				p.discard(r)
+				continue
+			}
+			return r, nil
+		default:
+		}
+
+		p.m.Lock()
+		if p.closed {
+			p.m.Unlock()
 			return nil, ErrPoolClosed
 		}
-		return r, nil
-	// Provide a new resource since there are none available.
-	default:
-		log.Println("Acquire:", "New resource")
-		return p.factory()
+		if p.numOpen < p.maxOpen {
+			// Reserve the slot in the same critical section as the check,
+			// so concurrent Acquire calls can't all pass the check before
+			// any of them increments numOpen.
+			p.numOpen++
+			p.m.Unlock()
+			p.log().Debugf("Acquire: new resource")
+			atomic.AddUint64(&p.emptyAcquireCount, 1)
+			return p.construct(ctx)
+		}
+		p.m.Unlock()
+
+		// At the cap: wait for a resource to come back or for ctx to end.
+		select {
+		case r, ok := <-p.resources:
+			if !ok {
+				return nil, ErrPoolClosed
+			}
+			if p.expired(r) {
+				p.discard(r)
+				continue
+			}
+			return r, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
 }
 
-// Release places a new resource into the pool.
-func (p *Pool) Release(r io.Closer) {
-	// Secure this operation with `Close`.
+// release places a resource back into the pool on behalf of Resource.Release.
+func (p *Pool[T]) release(r *Resource[T]) {
 	p.m.Lock()
 	defer p.m.Unlock()
 
 	// If the pool is closed, discard the resource.
 	if p.closed {
-		r.Close()
+		p.numOpen--
+		p.destructor(r.value)
 		return
 	}
 
 	select {
 	// Attempt to place new resource on the queue.
 	case p.resources <- r:
-		log.Println("Release:", "In queue")
+		p.log().Debugf("Release: in queue")
 	// If the queue is already at max capacity, close the resource.
 	default:
-		log.Println("Release:", "Closing")
-		r.Close()
+		p.numOpen--
+		p.log().Debugf("Release: closing")
+		p.destructor(r.value)
+	}
+}
+
+// evictLoop periodically evicts idle resources down to minOpen and tops the
+// pool back up if it has fallen below minOpen, until the pool is closed.
+func (p *Pool[T]) evictLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.evictOnce()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// evictOnce performs a single evict-then-top-up pass.
+func (p *Pool[T]) evictOnce() {
+	for {
+		p.m.Lock()
+		if p.closed || uint(len(p.resources)) <= p.minOpen {
+			p.m.Unlock()
+			break
+		}
+		p.m.Unlock()
+
+		select {
+		case r, ok := <-p.resources:
+			if !ok {
+				return
+			}
+			p.discard(r)
+		default:
+			return
+		}
+	}
+
+	for {
+		p.m.Lock()
+		closed, numOpen := p.closed, p.numOpen
+		p.m.Unlock()
+		if closed || numOpen >= p.minOpen {
+			return
+		}
+
+		r, err := p.newResource(context.Background())
+		if err != nil {
+			p.log().Warnf("evict: top-up failed: %v", err)
+			return
+		}
+
+		// Re-check closed and send inside the same critical section, so a
+		// concurrent Close can't close p.resources between the check and
+		// the send and turn this into a send-on-closed-channel panic.
+		p.m.Lock()
+		if p.closed {
+			p.m.Unlock()
+			p.discard(r)
+			return
+		}
+		select {
+		case p.resources <- r:
+			p.m.Unlock()
+		default:
+			p.m.Unlock()
+			p.discard(r)
+			return
+		}
+	}
+}
+
+// Stats returns a snapshot of the pool's counters, for monitoring pressure
+// and tuning minOpen/maxOpen.
+func (p *Pool[T]) Stats() Stats {
+	p.m.Lock()
+	idle := uint64(len(p.resources))
+	total := uint64(p.numOpen)
+	max := uint64(p.maxOpen)
+	p.m.Unlock()
+
+	return Stats{
+		AcquireCount:          atomic.LoadUint64(&p.acquireCount),
+		AcquireDuration:       time.Duration(atomic.LoadInt64(&p.acquireDurationNs)),
+		EmptyAcquireCount:     atomic.LoadUint64(&p.emptyAcquireCount),
+		CanceledAcquireCount:  atomic.LoadUint64(&p.canceledAcquireCount),
+		ConstructingResources: atomic.LoadUint64(&p.constructing),
+		IdleResources:         idle,
+		TotalResources:        total,
+		MaxResources:          max,
 	}
 }
 
-// Close shutsdown the pool and closes all existing resources.
-func (p *Pool) Close() {
+// Close shutsdown the pool and destroys all idle resources. Resources still
+// acquired are destroyed when they are released.
+func (p *Pool[T]) Close() {
 	p.m.Lock()
 	defer p.m.Unlock()
 
 	// If the pool is already closed, don't do anything.
 	if p.closed {
-		log.Println("Pool already closed.")
+		p.log().Warnf("Pool already closed.")
 		return
 	}
 
 	// Close the pool
-	log.Println("Closing pool..")
+	p.log().Infof("Closing pool..")
 	p.closed = true
+	close(p.done)
 
 	// Close the channel before draining it of its resources. Deadlock will occur if this is not done.
 	close(p.resources)
 
-	// Close the resources
+	// Destroy the resources
 	for r := range p.resources {
-		r.Close()
+		p.destructor(r.value)
 	}
 }
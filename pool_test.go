@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPool_MaxOpenCapUnderConcurrency guards against the maxOpen cap being
+// enforced via a stale check-then-increment: many concurrent Acquire calls
+// against a small maxOpen must never push TotalResources past it. Run with
+// -race.
+func TestPool_MaxOpenCapUnderConcurrency(t *testing.T) {
+	const maxOpen = 2
+	const goroutines = 50
+	const itersPerGoroutine = 20
+
+	p, err := New(
+		func(ctx context.Context) (int, error) { return 0, nil },
+		func(int) {},
+		0, maxOpen, 0, nil,
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer p.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < itersPerGoroutine; j++ {
+				r, err := p.Acquire(context.Background())
+				if err != nil {
+					t.Errorf("Acquire: %v", err)
+					return
+				}
+				if got := p.Stats().TotalResources; got > maxOpen {
+					t.Errorf("TotalResources = %d, want <= %d", got, maxOpen)
+				}
+				r.Release()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestPool_EvictTopUpDoesNotPanicOnConcurrentClose guards against evictOnce
+// sending a freshly constructed resource on p.resources after Close has
+// already closed that channel. Run with -race.
+func TestPool_EvictTopUpDoesNotPanicOnConcurrentClose(t *testing.T) {
+	var calls int32
+
+	p, err := New(
+		func(ctx context.Context) (int, error) {
+			n := atomic.AddInt32(&calls, 1)
+			if n > 1 {
+				// Slow down the top-up's constructor call so Close has a
+				// window to run while evictOnce is mid-construction.
+				time.Sleep(50 * time.Millisecond)
+			}
+			return int(n), nil
+		},
+		func(int) {},
+		1, 1, 0, nil,
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	r, err := p.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	r.Destroy() // drops numOpen below minOpen, so evictOnce will try to top up
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if rec := recover(); rec != nil {
+				t.Errorf("evictOnce panicked: %v", rec)
+			}
+		}()
+		p.evictOnce()
+	}()
+
+	time.Sleep(10 * time.Millisecond) // let evictOnce start constructing
+	p.Close()
+	wg.Wait()
+}
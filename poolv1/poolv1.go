@@ -0,0 +1,101 @@
+// Package poolv1 adapts the pre-generics, io.Closer-based pool.Pool API on
+// top of the generic pool.Pool[io.Closer], for callers that have not
+// migrated to the generic API yet.
+package poolv1
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gerardmrk/pool"
+)
+
+// ErrPoolClosed is returned when an Acquire returns on a closed pool.
+var ErrPoolClosed = pool.ErrPoolClosed
+
+// Stats is the v1 alias of pool.Stats.
+type Stats = pool.Stats
+
+// Pool is a thin io.Closer-based shim over pool.Pool[io.Closer].
+type Pool struct {
+	inner *pool.Pool[io.Closer]
+
+	m        sync.Mutex
+	inflight map[io.Closer]*pool.Resource[io.Closer]
+}
+
+// New creates a pool that manages io.Closer resources. fn takes the place of
+// the generic constructor, and each resource's Close is used as the
+// destructor; the sizing, lifetime and validate arguments are passed
+// through to pool.New unchanged.
+func New(fn func() (io.Closer, error), minOpen, maxOpen uint, maxLifetime time.Duration, validate func(io.Closer) bool) (*Pool, error) {
+	constructor := func(ctx context.Context) (io.Closer, error) {
+		return fn()
+	}
+
+	inner, err := pool.New(constructor, closeDestructor, minOpen, maxOpen, maxLifetime, validate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pool{
+		inner:    inner,
+		inflight: make(map[io.Closer]*pool.Resource[io.Closer]),
+	}, nil
+}
+
+func closeDestructor(r io.Closer) {
+	r.Close()
+}
+
+// Acquire retrieves a resource from the pool, blocking indefinitely once the
+// pool's max-open cap has been reached.
+func (p *Pool) Acquire() (io.Closer, error) {
+	return p.AcquireContext(context.Background())
+}
+
+// AcquireContext retrieves a resource from the pool; see pool.Pool.Acquire
+// for blocking/cancellation behavior.
+func (p *Pool) AcquireContext(ctx context.Context) (io.Closer, error) {
+	r, err := p.inner.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.m.Lock()
+	p.inflight[r.Value()] = r
+	p.m.Unlock()
+
+	return r.Value(), nil
+}
+
+// Release places a resource back into the pool.
+func (p *Pool) Release(c io.Closer) {
+	p.m.Lock()
+	r, ok := p.inflight[c]
+	if ok {
+		delete(p.inflight, c)
+	}
+	p.m.Unlock()
+
+	if !ok {
+		// Not one of ours (or already released twice); just close it.
+		c.Close()
+		return
+	}
+
+	r.Release()
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (p *Pool) Stats() Stats {
+	return p.inner.Stats()
+}
+
+// Close shutsdown the pool and closes all idle resources. Resources still
+// acquired are closed when they are released.
+func (p *Pool) Close() {
+	p.inner.Close()
+}
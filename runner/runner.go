@@ -0,0 +1,123 @@
+// Package runner executes a set of tasks against resources borrowed from a
+// pool, bounded by a timeout and interruptible by SIGINT/SIGTERM.
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gerardmrk/pool/poolv1"
+)
+
+// ErrTimeout is returned by Start when the timeout elapses before all tasks
+// complete.
+var ErrTimeout = errors.New("received timeout")
+
+// ErrInterrupt is returned by Start when the process receives SIGINT or
+// SIGTERM before all tasks complete.
+var ErrInterrupt = errors.New("received interrupt")
+
+// Runner executes a set of tasks in order, each borrowing a resource from a
+// pool, within a bounded timeout and subject to OS interrupt. A Runner may
+// be Start-ed more than once; each call runs the currently registered tasks
+// from scratch with its own timeout/interrupt/cancellation state.
+type Runner struct {
+	pool    *poolv1.Pool
+	timeout time.Duration
+	tasks   []func(id int, r io.Closer) error
+}
+
+// New creates a Runner that borrows resources from p and must finish all
+// registered tasks within timeout.
+func New(p *poolv1.Pool, timeout time.Duration) *Runner {
+	return &Runner{
+		pool:    p,
+		timeout: timeout,
+	}
+}
+
+// Add appends tasks to be run, in order, when Start is called.
+func (r *Runner) Add(tasks ...func(id int, r io.Closer) error) {
+	r.tasks = append(r.tasks, tasks...)
+}
+
+// Start runs the registered tasks and blocks until they complete, the
+// timeout elapses, or an interrupt is received. On timeout or interrupt,
+// ctx is canceled so a task blocked in AcquireContext unblocks too, rather
+// than leaking the run goroutine forever.
+func (r *Runner) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	// stop is closed at most once, broadcasting the interrupt to both this
+	// select and run's gotInterrupt check without either side consuming
+	// the other's notification.
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-sig:
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	complete := make(chan error, 1)
+	go r.run(ctx, stop, complete)
+
+	select {
+	case err := <-complete:
+		return err
+	case <-stop:
+		return ErrInterrupt
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
+
+// run executes the registered tasks in index order, stopping early if an
+// interrupt arrives between tasks so that progress made so far is kept.
+// complete is buffered, so this send never blocks even if Start has already
+// returned via stop or ctx.Done.
+func (r *Runner) run(ctx context.Context, stop <-chan struct{}, complete chan<- error) {
+	for id, task := range r.tasks {
+		if gotInterrupt(stop) {
+			break
+		}
+
+		res, err := r.pool.AcquireContext(ctx)
+		if err != nil {
+			complete <- err
+			return
+		}
+
+		err = task(id, res)
+		r.pool.Release(res)
+		if err != nil {
+			complete <- err
+			return
+		}
+	}
+
+	complete <- nil
+}
+
+// gotInterrupt non-blockingly checks whether an interrupt has arrived.
+func gotInterrupt(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
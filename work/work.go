@@ -0,0 +1,68 @@
+// Package work provides a worker-pool dispatcher that submits jobs against
+// resources borrowed from a pool.
+package work
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gerardmrk/pool/poolv1"
+)
+
+// Worker is implemented by jobs submitted to a Pool.
+type Worker interface {
+	Task(r io.Closer)
+}
+
+// Pool dispatches Workers to a fixed number of goroutines, each borrowing a
+// resource from the underlying pool for the duration of a task.
+type Pool struct {
+	pool *poolv1.Pool
+	work chan Worker
+	wg   sync.WaitGroup
+}
+
+// New creates a dispatcher that runs up to maxGoroutines Workers
+// concurrently, each against a resource acquired from p.
+func New(p *poolv1.Pool, maxGoroutines int) *Pool {
+	d := &Pool{
+		pool: p,
+		work: make(chan Worker),
+	}
+
+	d.wg.Add(maxGoroutines)
+	for i := 0; i < maxGoroutines; i++ {
+		go d.worker()
+	}
+
+	return d
+}
+
+func (d *Pool) worker() {
+	defer d.wg.Done()
+
+	for w := range d.work {
+		r, err := d.pool.Acquire()
+		if err != nil {
+			// Pool closed (or otherwise unable to produce a resource):
+			// nothing to hand the Worker, so drop this job rather than
+			// calling Task/Release with a nil resource.
+			continue
+		}
+		w.Task(r)
+		d.pool.Release(r)
+	}
+}
+
+// Run submits w to be run by the next available worker, blocking until one
+// is free.
+func (d *Pool) Run(w Worker) {
+	d.work <- w
+}
+
+// Shutdown waits for all in-flight work to finish, then stops every worker
+// goroutine.
+func (d *Pool) Shutdown() {
+	close(d.work)
+	d.wg.Wait()
+}